@@ -0,0 +1,171 @@
+package logrusrotate
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// OverflowPolicy controls what Write does when the async buffer channel is
+// full.
+type OverflowPolicy int
+
+const (
+	// BlockOnFull makes Write wait for room in the buffer, trading
+	// throughput for not losing messages.
+	BlockOnFull OverflowPolicy = iota
+	// DropOnFull makes Write return immediately with an error instead of
+	// waiting, trading durability for throughput.
+	DropOnFull
+)
+
+const defaultAsyncBufferSize = 1024
+
+var asyncBufPool = sync.Pool{
+	New: func() interface{} { return make([]byte, 0, 256) },
+}
+
+// asyncOp is the unit of work sent to the async writer goroutine. A plain
+// write carries data; rotate/closeOp carry no data and report completion on
+// done so Rotate and Close can block until they are actually applied,
+// keeping size accounting consistent with the writes around them.
+type asyncOp struct {
+	data    []byte
+	rotate  bool
+	closeOp bool
+	done    chan error
+}
+
+// asyncStart lazily spins up the goroutine that owns l.file once Async
+// writes are first used.
+func (l *Logger) asyncStart() {
+	l.asyncStartOnce.Do(func() {
+		size := l.AsyncBufferSize
+		if size <= 0 {
+			size = defaultAsyncBufferSize
+		}
+		l.asyncChan = make(chan asyncOp, size)
+		go l.asyncRun()
+	})
+}
+
+// writeAsync copies p into a pooled buffer and enqueues it, returning
+// without touching disk. The actual write happens later on the async
+// writer goroutine.
+//
+// asyncMu makes "is it closed" and "enqueue the op" a single atomic step,
+// shared with closeAsync. Without it, a write could see asyncClosed still
+// false, then enqueue into asyncChan after asyncRun had already drained it
+// and returned from closeOp, reporting success for data nobody would ever
+// write to disk.
+func (l *Logger) writeAsync(p []byte) (n int, err error) {
+	l.asyncStart()
+
+	buf := asyncBufPool.Get().([]byte)[:0]
+	buf = append(buf, p...)
+	op := asyncOp{data: buf}
+
+	l.asyncMu.Lock()
+	defer l.asyncMu.Unlock()
+
+	if l.asyncClosed {
+		asyncBufPool.Put(buf[:0])
+		return 0, fmt.Errorf("logrusrotate: write after Close")
+	}
+
+	if l.OverflowPolicy == DropOnFull {
+		select {
+		case l.asyncChan <- op:
+			return len(p), nil
+		default:
+			asyncBufPool.Put(buf[:0])
+			return 0, fmt.Errorf("async write buffer full, message dropped")
+		}
+	}
+
+	// BlockOnFull holds asyncMu for as long as the channel stays full: the
+	// send must be ordered against closeAsync/rotateAsync's own send under
+	// the same lock to preserve correctness, which means Close/Rotate queue
+	// up behind a blocked writer here rather than racing it for the next
+	// freed buffer slot. Sustained backpressure can therefore delay Close
+	// longer than before; that's the accepted cost of never losing a write.
+	l.asyncChan <- op
+	return len(p), nil
+}
+
+// rotateAsync asks the async writer goroutine to rotate, waiting for it to
+// run so size accounting stays consistent with surrounding writes.
+func (l *Logger) rotateAsync() (err error) {
+	l.asyncStart()
+
+	done := make(chan error, 1)
+	l.asyncMu.Lock()
+	if l.asyncClosed {
+		l.asyncMu.Unlock()
+		return fmt.Errorf("logrusrotate: rotate after Close")
+	}
+	l.asyncChan <- asyncOp{rotate: true, done: done}
+	l.asyncMu.Unlock()
+
+	return <-done
+}
+
+// closeAsync asks the async writer goroutine to drain whatever is still
+// queued, fsync and close the file, then stop. It is safe to call more than
+// once; later calls are no-ops once asyncClosed is set.
+func (l *Logger) closeAsync() (err error) {
+	l.asyncStart()
+
+	done := make(chan error, 1)
+	l.asyncMu.Lock()
+	if l.asyncClosed {
+		l.asyncMu.Unlock()
+		return nil
+	}
+	l.asyncClosed = true
+	l.asyncChan <- asyncOp{closeOp: true, done: done}
+	l.asyncMu.Unlock()
+
+	return <-done
+}
+
+// asyncRun is the sole goroutine allowed to advance l.file once Async is
+// enabled. It still takes l.lock around each op so that readers like
+// compressOldLogFiles and followActiveFile, which read l.logfileFullName
+// from other goroutines, never observe a half-written field. It processes writes, rotates and the final close strictly in the
+// order they were enqueued.
+func (l *Logger) asyncRun() {
+	for op := range l.asyncChan {
+		switch {
+		case op.closeOp:
+			l.lock.Lock()
+			err := l.closeAndSync()
+			l.lock.Unlock()
+			op.done <- err
+			return
+		case op.rotate:
+			l.lock.Lock()
+			err := l.rotateImmediately()
+			l.lock.Unlock()
+			op.done <- err
+		default:
+			l.lock.Lock()
+			_, err := l.writeSync(op.data)
+			l.lock.Unlock()
+			asyncBufPool.Put(op.data[:0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "logrusrotate: async write failed: %s\n", err)
+			}
+		}
+	}
+}
+
+func (l *Logger) closeAndSync() error {
+	if l.file == nil {
+		return nil
+	}
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+	return l.close()
+}
@@ -0,0 +1,103 @@
+package logrusrotate
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	logFileExt     = ".log"
+	compressedExt  = ".log.gz"
+	compressMinAge = 5 * time.Second
+)
+
+// compressOldLogFiles gzips every rotated-out log file in logDir, skipping
+// the currently active file and anything younger than compressMinAge so it
+// doesn't race with a writer that just rotated. It reuses allLogFiles, the
+// same candidate list retention uses, so it skips the "current" symlink
+// (or its Windows pointer-file fallback) exactly the way retention does,
+// instead of risking following it and compressing/removing the live file.
+func (l *Logger) compressOldLogFiles() {
+	if !l.Compress {
+		return
+	}
+
+	l.lock.Lock()
+	activeName := l.logfileFullName
+	l.lock.Unlock()
+
+	files, err := l.allLogFiles()
+	if err != nil {
+		return
+	}
+
+	for _, f := range files {
+		if !strings.HasSuffix(f.FileInfo.Name(), logFileExt) {
+			continue
+		}
+		full := filepath.Join(l.logDir, f.FileInfo.Name())
+		if full == activeName {
+			continue
+		}
+		if time.Since(f.ModTime()) < compressMinAge {
+			continue
+		}
+		compressFile(full)
+	}
+}
+
+// compressFile gzips src into a ".log.gz" file next to it, fsyncs the
+// result and removes the original on success. Errors are left for the
+// caller to decide whether to retry; a partially written ".tmp" file is
+// always cleaned up.
+func compressFile(src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("can't open log file to compress: %s", err)
+	}
+	defer in.Close()
+
+	tmpName := src + ".tmp"
+	out, err := os.OpenFile(tmpName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("can't create compressed tmp file: %s", err)
+	}
+
+	if err := gzipCopy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmpName)
+		return err
+	}
+
+	if err := out.Sync(); err != nil {
+		out.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("can't fsync compressed file: %s", err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("can't close compressed file: %s", err)
+	}
+
+	finalName := strings.TrimSuffix(src, logFileExt) + compressedExt
+	if err := os.Rename(tmpName, finalName); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("can't rename compressed file: %s", err)
+	}
+
+	return os.Remove(src)
+}
+
+func gzipCopy(dst io.Writer, src io.Reader) error {
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		gz.Close()
+		return fmt.Errorf("can't compress log file: %s", err)
+	}
+	return gz.Close()
+}
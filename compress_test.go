@@ -0,0 +1,91 @@
+package logrusrotate
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCompressOldLogFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logrusrotate-compress")
+	if err != nil {
+		t.Fatalf("TempDir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	oldName := filepath.Join(dir, "app.pid1.old.log")
+	if err := ioutil.WriteFile(oldName, []byte("old content\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+	old := time.Now().Add(-2 * compressMinAge)
+	if err := os.Chtimes(oldName, old, old); err != nil {
+		t.Fatalf("Chtimes: %s", err)
+	}
+
+	activeName := filepath.Join(dir, "app.pid1.active.log")
+	if err := ioutil.WriteFile(activeName, []byte("active content\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	l := &Logger{
+		logDir:          dir,
+		logfileBaseName: "app",
+		logfileFullName: activeName,
+		Compress:        true,
+	}
+
+	l.compressOldLogFiles()
+
+	if _, err := os.Stat(oldName); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed after compression, stat err: %v", oldName, err)
+	}
+
+	gzName := filepath.Join(dir, "app.pid1.old.log.gz")
+	f, err := os.Open(gzName)
+	if err != nil {
+		t.Fatalf("expected compressed file %s: %s", gzName, err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %s", err)
+	}
+	defer gz.Close()
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(gz); err != nil {
+		t.Fatalf("read compressed content: %s", err)
+	}
+	if buf.String() != "old content\n" {
+		t.Fatalf("compressed content = %q, want %q", buf.String(), "old content\n")
+	}
+
+	if _, err := os.Stat(activeName); err != nil {
+		t.Fatalf("active file should be left untouched: %s", err)
+	}
+}
+
+func TestCompressOldLogFilesSkipsRecent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logrusrotate-compress-recent")
+	if err != nil {
+		t.Fatalf("TempDir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	recentName := filepath.Join(dir, "app.pid1.recent.log")
+	if err := ioutil.WriteFile(recentName, []byte("recent\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	l := &Logger{logDir: dir, logfileBaseName: "app", Compress: true}
+	l.compressOldLogFiles()
+
+	if _, err := os.Stat(recentName); err != nil {
+		t.Fatalf("recent file should not be compressed yet: %s", err)
+	}
+}
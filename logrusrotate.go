@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,17 +25,29 @@ const (
 var _ io.WriteCloser = (*Logger)(nil)
 
 type Logger struct {
-	MaxSizeMb       int
-	MaxAge          int
-	MaxBackups      int
-	size            int64
-	file            *os.File
-	lock            sync.Mutex
-	rotateTaskChan  chan bool
-	taskStartOnce   sync.Once
-	logDir          string
-	logfileBaseName string
-	logfileFullName string
+	MaxSizeMb          int
+	MaxAge             int
+	MaxBackups         int
+	Compress           bool
+	RotationInterval   time.Duration
+	RotationTimeFormat string
+	Async              bool
+	AsyncBufferSize    int
+	OverflowPolicy     OverflowPolicy
+	CurrentSymlink     string
+	size               int64
+	file               *os.File
+	lock               sync.Mutex
+	rotateTaskChan     chan bool
+	taskStartOnce      sync.Once
+	logDir             string
+	logfileBaseName    string
+	logfileFullName    string
+	bucketStart        time.Time
+	asyncChan          chan asyncOp
+	asyncStartOnce     sync.Once
+	asyncMu            sync.Mutex
+	asyncClosed        bool
 }
 
 func NewLogger() *Logger {
@@ -79,10 +92,17 @@ func NewHook(defaultLogger *Logger, minLevel logrus.Level, formatter logrus.Form
 				continue
 			}
 			hook.loggerByLevel[level] = &Logger{
-				logfileFullName: config.logfileFullName,
-				MaxSizeMb:       config.MaxSizeMb,
-				MaxBackups:      config.MaxBackups,
-				MaxAge:          config.MaxAge,
+				logfileFullName:    config.logfileFullName,
+				MaxSizeMb:          config.MaxSizeMb,
+				MaxBackups:         config.MaxBackups,
+				MaxAge:             config.MaxAge,
+				Compress:           config.Compress,
+				RotationInterval:   config.RotationInterval,
+				RotationTimeFormat: config.RotationTimeFormat,
+				Async:              config.Async,
+				AsyncBufferSize:    config.AsyncBufferSize,
+				OverflowPolicy:     config.OverflowPolicy,
+				CurrentSymlink:     config.CurrentSymlink,
 			}
 		}
 	}
@@ -110,9 +130,6 @@ func (hook *Hook) Levels() []logrus.Level {
 }
 
 func (l *Logger) Write(p []byte) (n int, err error) {
-	l.lock.Lock()
-	defer l.lock.Unlock()
-
 	writeLen := int64(len(p))
 	if writeLen > l.max() {
 		return 0, fmt.Errorf(
@@ -120,13 +137,30 @@ func (l *Logger) Write(p []byte) (n int, err error) {
 		)
 	}
 
+	if l.Async {
+		return l.writeAsync(p)
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.writeSync(p)
+}
+
+// writeSync writes p to the active file, opening or rotating it as needed.
+// The caller must either hold l.lock or be the sole goroutine allowed to
+// touch l.file, as is the case for the async writer goroutine.
+func (l *Logger) writeSync(p []byte) (n int, err error) {
 	if l.file == nil {
 		if err = l.openOrNew(len(p)); err != nil {
 			return 0, err
 		}
+	} else if l.RotationInterval > 0 && !l.currentBucket().Equal(l.bucketStart) {
+		if err = l.rotateImmediately(); err != nil {
+			return 0, err
+		}
 	}
 
-	if l.size+writeLen > l.max() {
+	if l.size+int64(len(p)) > l.max() {
 		if err := l.rotateImmediately(); err != nil {
 			return 0, err
 		}
@@ -139,6 +173,9 @@ func (l *Logger) Write(p []byte) (n int, err error) {
 }
 
 func (l *Logger) Close() error {
+	if l.Async {
+		return l.closeAsync()
+	}
 	l.lock.Lock()
 	defer l.lock.Unlock()
 	return l.close()
@@ -154,6 +191,9 @@ func (l *Logger) close() error {
 }
 
 func (l *Logger) Rotate() error {
+	if l.Async {
+		return l.rotateAsync()
+	}
 	l.lock.Lock()
 	defer l.lock.Unlock()
 	return l.rotateImmediately()
@@ -176,11 +216,25 @@ func newFileName(logDir, logfileBaseName string) string {
 }
 
 func (l *Logger) openNew() error {
-	l.logfileFullName = newFileName(l.logDir, l.logfileBaseName)
-	err := os.MkdirAll(l.logDir, 0744)
-	if err != nil {
+	if err := os.MkdirAll(l.logDir, 0744); err != nil {
 		return fmt.Errorf("can't mkdir :%s. error: %s", l.logDir, err)
 	}
+
+	if l.RotationInterval > 0 {
+		bucket := l.currentBucket()
+		if !l.bucketStart.IsZero() && bucket.Equal(l.bucketStart) {
+			// the bucket hasn't rolled over yet: this rotation was forced
+			// by MaxSizeMb, so fall back to a uniquely timestamped name
+			// instead of truncating the bucket's file
+			l.logfileFullName = newFileName(l.logDir, l.logfileBaseName)
+		} else {
+			l.logfileFullName = l.bucketFileName(bucket)
+		}
+		l.bucketStart = bucket
+	} else {
+		l.logfileFullName = newFileName(l.logDir, l.logfileBaseName)
+	}
+
 	mode := os.FileMode(0644)
 	f, err := os.OpenFile(l.logfileFullName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
 	if err != nil {
@@ -188,11 +242,23 @@ func (l *Logger) openNew() error {
 	}
 	l.file = f
 	l.size = 0
+	l.updateCurrentSymlink()
 	return nil
 }
 
 func (l *Logger) openOrNew(writeLen int) error {
 	l.rotateTaskStart()
+
+	if l.RotationInterval > 0 {
+		bucket := l.currentBucket()
+		l.logfileFullName = l.bucketFileName(bucket)
+		if _, err := os.Stat(l.logfileFullName); err == nil {
+			// the bucket file already exists, so a size-forced rotation
+			// from here must fall back instead of truncating it
+			l.bucketStart = bucket
+		}
+	}
+
 	info, err := os.Stat(l.logfileFullName)
 	if os.IsNotExist(err) {
 		return l.openNew()
@@ -211,10 +277,15 @@ func (l *Logger) openOrNew(writeLen int) error {
 	}
 	l.file = file
 	l.size = info.Size()
+	if l.RotationInterval > 0 {
+		l.bucketStart = l.currentBucket()
+	}
 	return nil
 }
 
 func (l *Logger) rotateRunOnce() error {
+	l.compressOldLogFiles()
+
 	if l.MaxBackups == 0 && l.MaxAge == 0 {
 		return nil
 	}
@@ -297,9 +368,13 @@ func (l *Logger) allLogFiles() ([]LogInfo, error) {
 		return nil, fmt.Errorf("can NOT access directory: %s", err)
 	}
 	logFiles := []LogInfo{}
+	currentName := filepath.Base(l.currentSymlinkPath())
 
 	for _, f := range files {
-		if f.IsDir() {
+		if f.IsDir() || f.Name() == currentName {
+			continue
+		}
+		if !strings.HasSuffix(f.Name(), logFileExt) && !strings.HasSuffix(f.Name(), compressedExt) {
 			continue
 		}
 		logFiles = append(logFiles, LogInfo{f.ModTime(), f})
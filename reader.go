@@ -0,0 +1,265 @@
+package logrusrotate
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// tailPollInterval is how often Tail checks the active file for new data and
+// rotations; Tail always polls and uses no OS-level file watcher.
+const tailPollInterval = 500 * time.Millisecond
+
+// Open returns a single stream concatenating every retained log file in
+// chronological order, transparently decompressing ".log.gz" backups, and
+// ending with the live file. This gives callers an in-process equivalent of
+// `docker logs` over rotated and compressed files without shelling out.
+func (l *Logger) Open() (io.ReadCloser, error) {
+	names, err := l.orderedLogFileNames()
+	if err != nil {
+		return nil, err
+	}
+	return &multiLogReader{names: names}, nil
+}
+
+// orderedLogFileNames returns the retained log files oldest first.
+func (l *Logger) orderedLogFileNames() ([]string, error) {
+	files, err := l.allLogFiles()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[len(files)-1-i] = filepath.Join(l.logDir, f.FileInfo.Name())
+	}
+	return names, nil
+}
+
+// multiLogReader lazily opens each named file in turn, decompressing ".gz"
+// files on the fly, and presents them as a single contiguous stream.
+type multiLogReader struct {
+	names   []string
+	idx     int
+	current io.ReadCloser
+	gz      *gzip.Reader
+}
+
+func (m *multiLogReader) Read(p []byte) (int, error) {
+	for {
+		if m.current == nil {
+			if m.idx >= len(m.names) {
+				return 0, io.EOF
+			}
+			name := m.names[m.idx]
+			m.idx++
+
+			f, err := os.Open(name)
+			if err != nil {
+				if os.IsNotExist(err) {
+					// retention removed it between listing and reading
+					continue
+				}
+				return 0, err
+			}
+
+			if strings.HasSuffix(name, compressedExt) {
+				gz, err := gzip.NewReader(f)
+				if err != nil {
+					f.Close()
+					return 0, fmt.Errorf("can't read compressed log %s: %s", name, err)
+				}
+				m.gz = gz
+			}
+			m.current = f
+		}
+
+		var (
+			n   int
+			err error
+		)
+		if m.gz != nil {
+			n, err = m.gz.Read(p)
+		} else {
+			n, err = m.current.Read(p)
+		}
+
+		if err == io.EOF {
+			if m.gz != nil {
+				m.gz.Close()
+				m.gz = nil
+			}
+			m.current.Close()
+			m.current = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+
+		return n, err
+	}
+}
+
+func (m *multiLogReader) Close() error {
+	if m.gz != nil {
+		m.gz.Close()
+	}
+	if m.current != nil {
+		return m.current.Close()
+	}
+	return nil
+}
+
+// Tail streams the last n lines already on disk followed by every line
+// written afterwards, until ctx is cancelled. It polls the logger's active
+// file name for rotations instead of depending on an OS-level file watcher,
+// reopening the active file whenever that name changes.
+func (l *Logger) Tail(ctx context.Context, n int) (<-chan []byte, error) {
+	initial, err := l.tailLastLines(n)
+	if err != nil {
+		return nil, err
+	}
+
+	// Capture the active file and its size right after seeding so that bytes
+	// appended after this point and before the first poll are still picked
+	// up, instead of being skipped as "already delivered". Bytes appended
+	// between the end of the seed read and this Stat are not delivered.
+	l.lock.Lock()
+	startPath := l.logfileFullName
+	l.lock.Unlock()
+	var startOffset int64
+	if startPath != "" {
+		if info, err := os.Stat(startPath); err == nil {
+			startOffset = info.Size()
+		}
+	}
+
+	out := make(chan []byte)
+	go func() {
+		defer close(out)
+		for _, line := range initial {
+			select {
+			case out <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		l.followActiveFile(ctx, out, startPath, startOffset)
+	}()
+
+	return out, nil
+}
+
+// tailLastLines reads the full retained history and keeps only the last n
+// lines, the same way `tail -n` seeds itself before following.
+func (l *Logger) tailLastLines(n int) ([][]byte, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	r, err := l.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	ring := make([][]byte, 0, n)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		ring = append(ring, append([]byte(nil), scanner.Bytes()...))
+		if len(ring) > n {
+			ring = ring[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return ring, nil
+}
+
+// followActiveFile polls the logger's active file for new data and for
+// rotations (the path changing), emitting each newly written line on out.
+// initialPath/initialOffset seed the first file so content already
+// delivered by tailLastLines isn't replayed; any later rotation starts its
+// new file from offset 0, since nothing has been sent from it yet.
+func (l *Logger) followActiveFile(ctx context.Context, out chan<- []byte, initialPath string, initialOffset int64) {
+	var (
+		path    = initialPath
+		offset  = initialOffset
+		partial []byte
+		file    *os.File
+	)
+	if path != "" {
+		if f, err := os.Open(path); err == nil {
+			file = f
+		} else {
+			path = ""
+		}
+	}
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+
+	ticker := time.NewTicker(tailPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		l.lock.Lock()
+		activePath := l.logfileFullName
+		l.lock.Unlock()
+		if activePath == "" {
+			continue
+		}
+
+		if activePath != path {
+			if file != nil {
+				file.Close()
+			}
+			f, err := os.Open(activePath)
+			if err != nil {
+				continue
+			}
+			// a freshly rotated file starts empty, so nothing has been
+			// sent from it yet: always follow it from the beginning.
+			file, path, offset, partial = f, activePath, 0, nil
+		}
+
+		info, err := file.Stat()
+		if err != nil || info.Size() <= offset {
+			continue
+		}
+
+		buf := make([]byte, info.Size()-offset)
+		if _, err := file.ReadAt(buf, offset); err != nil && err != io.EOF {
+			continue
+		}
+		offset = info.Size()
+
+		lines := bytes.Split(append(partial, buf...), []byte("\n"))
+		partial = lines[len(lines)-1]
+		for _, line := range lines[:len(lines)-1] {
+			select {
+			case out <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package logrusrotate
+
+import (
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeGzipFile(t *testing.T, name string, content string) {
+	t.Helper()
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatalf("Create %s: %s", name, err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	if _, err := gz.Write([]byte(content)); err != nil {
+		t.Fatalf("gzip write: %s", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip close: %s", err)
+	}
+}
+
+func TestOpenConcatenatesChronologically(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logrusrotate-open")
+	if err != nil {
+		t.Fatalf("TempDir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	oldest := filepath.Join(dir, "app.pid1.a.log")
+	middle := filepath.Join(dir, "app.pid1.b.log.gz")
+	newest := filepath.Join(dir, "app.pid1.c.log")
+
+	if err := ioutil.WriteFile(oldest, []byte("first\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+	writeGzipFile(t, middle, "second\n")
+	if err := ioutil.WriteFile(newest, []byte("third\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	now := time.Now()
+	os.Chtimes(oldest, now.Add(-20*time.Second), now.Add(-20*time.Second))
+	os.Chtimes(middle, now.Add(-10*time.Second), now.Add(-10*time.Second))
+	os.Chtimes(newest, now, now)
+
+	l := &Logger{logDir: dir, logfileBaseName: "app"}
+
+	r, err := l.Open()
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+	defer r.Close()
+
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %s", err)
+	}
+
+	want := "first\nsecond\nthird\n"
+	if string(content) != want {
+		t.Fatalf("content = %q, want %q", content, want)
+	}
+}
+
+func TestTailSeedsAndFollows(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logrusrotate-tail")
+	if err != nil {
+		t.Fatalf("TempDir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	live := filepath.Join(dir, "app.pid1.live.log")
+	if err := ioutil.WriteFile(live, []byte("l1\nl2\nl3\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	l := &Logger{logDir: dir, logfileBaseName: "app", logfileFullName: live}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	ch, err := l.Tail(ctx, 2)
+	if err != nil {
+		t.Fatalf("Tail: %s", err)
+	}
+
+	wantSeed := []string{"l2", "l3"}
+	for _, want := range wantSeed {
+		select {
+		case line := <-ch:
+			if string(line) != want {
+				t.Fatalf("seeded line = %q, want %q", line, want)
+			}
+		case <-time.After(1 * time.Second):
+			t.Fatalf("timed out waiting for seeded line %q", want)
+		}
+	}
+
+	f, err := os.OpenFile(live, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("OpenFile append: %s", err)
+	}
+	if _, err := f.WriteString("l4\n"); err != nil {
+		t.Fatalf("append write: %s", err)
+	}
+	f.Close()
+
+	select {
+	case line := <-ch:
+		if string(line) != "l4" {
+			t.Fatalf("followed line = %q, want %q", line, "l4")
+		}
+	case <-time.After(1500 * time.Millisecond):
+		t.Fatalf("timed out waiting for followed line")
+	}
+}
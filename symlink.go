@@ -0,0 +1,44 @@
+package logrusrotate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// currentSymlinkPath returns the path of the stable "current" pointer,
+// defaulting to "<logfileBaseName>.log" inside logDir when CurrentSymlink
+// is left blank.
+func (l *Logger) currentSymlinkPath() string {
+	name := l.CurrentSymlink
+	if name == "" {
+		name = l.logfileBaseName + ".log"
+	}
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(l.logDir, name)
+}
+
+// updateCurrentSymlink atomically points the "current" symlink (or, on
+// platforms without symlink support, a small pointer file) at the just
+// created active log file, so external tailers like `tail -F`, Filebeat or
+// Promtail always have a predictable path to watch.
+func (l *Logger) updateCurrentSymlink() {
+	link := l.currentSymlinkPath()
+	if link == l.logfileFullName {
+		return
+	}
+
+	tmp := link + ".tmp"
+	os.Remove(tmp)
+
+	if err := linkCurrent(l.logfileFullName, tmp); err != nil {
+		fmt.Fprintf(os.Stderr, "logrusrotate: can't point %s at %s: %s\n", link, l.logfileFullName, err)
+		return
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		fmt.Fprintf(os.Stderr, "logrusrotate: can't activate %s: %s\n", link, err)
+		os.Remove(tmp)
+	}
+}
@@ -0,0 +1,132 @@
+package logrusrotate
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestUpdateCurrentSymlinkTracksRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logrusrotate-symlink-rotate")
+	if err != nil {
+		t.Fatalf("TempDir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := &Logger{logDir: dir, logfileBaseName: "app"}
+
+	if _, err := l.Write([]byte("first\n")); err != nil {
+		t.Fatalf("Write: %s", err)
+	}
+	firstTarget := l.logfileFullName
+	assertSymlinkTarget(t, l.currentSymlinkPath(), firstTarget)
+
+	// newFileName only has second resolution, so give the clock room to
+	// tick over and produce a genuinely different rotated name.
+	time.Sleep(1100 * time.Millisecond)
+
+	if err := l.Rotate(); err != nil {
+		t.Fatalf("Rotate: %s", err)
+	}
+	if _, err := l.Write([]byte("second\n")); err != nil {
+		t.Fatalf("Write: %s", err)
+	}
+	secondTarget := l.logfileFullName
+	if secondTarget == firstTarget {
+		t.Fatalf("expected Rotate to produce a new file name")
+	}
+	assertSymlinkTarget(t, l.currentSymlinkPath(), secondTarget)
+}
+
+func TestRetentionSkipsCurrentSymlink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logrusrotate-symlink-retention")
+	if err != nil {
+		t.Fatalf("TempDir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := &Logger{logDir: dir, logfileBaseName: "app", MaxBackups: 1}
+
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			// newFileName only has second resolution; wait so each
+			// rotation creates a distinct file for retention to trim.
+			time.Sleep(1100 * time.Millisecond)
+			if err := l.Rotate(); err != nil {
+				t.Fatalf("Rotate: %s", err)
+			}
+		}
+		if _, err := l.Write([]byte("line\n")); err != nil {
+			t.Fatalf("Write: %s", err)
+		}
+	}
+	l.Close()
+
+	if err := l.rotateRunOnce(); err != nil {
+		t.Fatalf("rotateRunOnce: %s", err)
+	}
+
+	// the symlink must survive retention even though MaxBackups trimmed
+	// the real rotated files down to one.
+	assertSymlinkTarget(t, l.currentSymlinkPath(), l.logfileFullName)
+	if _, err := os.Stat(l.logfileFullName); err != nil {
+		t.Fatalf("active file should still exist after retention: %s", err)
+	}
+}
+
+func TestCompressDoesNotFollowCurrentSymlink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logrusrotate-symlink-compress")
+	if err != nil {
+		t.Fatalf("TempDir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := &Logger{logDir: dir, logfileBaseName: "app", Compress: true}
+
+	if _, err := l.Write([]byte("active content\n")); err != nil {
+		t.Fatalf("Write: %s", err)
+	}
+	activeTarget := l.logfileFullName
+	linkPath := l.currentSymlinkPath()
+	assertSymlinkTarget(t, linkPath, activeTarget)
+
+	// the default "current" symlink name ("app.log") also ends in
+	// logFileExt, which is exactly what let a naive directory scan treat it
+	// as just another rotated-out file once it aged past compressMinAge;
+	// backdate the active file itself to simulate that low-traffic logger.
+	old := time.Now().Add(-2 * compressMinAge)
+	if err := os.Chtimes(activeTarget, old, old); err != nil {
+		t.Fatalf("Chtimes: %s", err)
+	}
+
+	l.compressOldLogFiles()
+
+	if _, err := os.Lstat(linkPath); err != nil {
+		t.Fatalf("expected the current symlink to survive compression: %s", err)
+	}
+	assertSymlinkTarget(t, linkPath, activeTarget)
+
+	if _, err := os.Stat(activeTarget); err != nil {
+		t.Fatalf("active file should be untouched by compression: %s", err)
+	}
+	if _, err := os.Stat(activeTarget + ".gz"); !os.IsNotExist(err) {
+		t.Fatalf("compression must not have run against the symlink's target")
+	}
+}
+
+// assertSymlinkTarget resolves the current-symlink the way external tailers
+// would (following the platform-specific linkCurrent implementation: a real
+// symlink on unix, a pointer file on windows) and fails the test if it
+// doesn't point at want.
+func assertSymlinkTarget(t *testing.T, link, want string) {
+	t.Helper()
+
+	got, err := readCurrentPointer(link)
+	if err != nil {
+		t.Fatalf("readCurrentPointer(%s): %s", link, err)
+	}
+	if got != want {
+		t.Fatalf("current pointer = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+// +build !windows
+
+package logrusrotate
+
+import "os"
+
+// linkCurrent creates linkName as a symlink to target.
+func linkCurrent(target, linkName string) error {
+	return os.Symlink(target, linkName)
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+// +build !windows
+
+package logrusrotate
+
+import "os"
+
+// readCurrentPointer follows the real symlink created by linkCurrent.
+func readCurrentPointer(link string) (string, error) {
+	return os.Readlink(link)
+}
@@ -0,0 +1,15 @@
+//go:build windows
+// +build windows
+
+package logrusrotate
+
+import "io/ioutil"
+
+// readCurrentPointer reads the pointer file created by linkCurrent.
+func readCurrentPointer(link string) (string, error) {
+	b, err := ioutil.ReadFile(link)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
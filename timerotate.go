@@ -0,0 +1,38 @@
+package logrusrotate
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// defaultRotationTimeFormat is used to render the truncated bucket into the
+// filename when RotationTimeFormat is left blank.
+const defaultRotationTimeFormat = "2006-01-02"
+
+// currentBucket truncates now to the configured RotationInterval, giving the
+// wall-clock bucket the active file belongs to. time.Truncate rounds
+// relative to the zero time in UTC, which would rotate at a fixed UTC
+// offset rather than local midnight/hour on any non-UTC server, so the
+// local zone offset is added back in before and removed after truncating.
+func (l *Logger) currentBucket() time.Time {
+	now := time.Now()
+	_, offsetSec := now.Zone()
+	offset := time.Duration(offsetSec) * time.Second
+	return now.Add(offset).Truncate(l.RotationInterval).Add(-offset)
+}
+
+// bucketFileName builds the predictable, time-bucketed filename used when
+// RotationInterval is set, e.g. "app.pid123.2024-01-15.log". Since the name
+// only depends on the pid and the bucket, a Logger reopened by the same
+// process within the same bucket resolves to the same path and appends to it
+// via openOrNew. A restarted process has a new pid and so starts its own file.
+func (l *Logger) bucketFileName(bucket time.Time) string {
+	format := l.RotationTimeFormat
+	if format == "" {
+		format = defaultRotationTimeFormat
+	}
+	pid := strconv.Itoa(os.Getpid())
+	return filepath.Join(l.logDir, l.logfileBaseName+".pid"+pid+"."+bucket.Format(format)+".log")
+}
@@ -0,0 +1,105 @@
+package logrusrotate
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCurrentBucketTruncatesToInterval(t *testing.T) {
+	l := &Logger{RotationInterval: time.Hour}
+
+	bucket := l.currentBucket()
+	if bucket.Minute() != 0 || bucket.Second() != 0 || bucket.Nanosecond() != 0 {
+		t.Fatalf("bucket = %v, want truncated to the hour", bucket)
+	}
+
+	_, offsetSec := time.Now().Zone()
+	_, bucketOffsetSec := bucket.Zone()
+	if offsetSec != bucketOffsetSec {
+		t.Fatalf("bucket zone offset = %d, want local offset %d", bucketOffsetSec, offsetSec)
+	}
+}
+
+func TestOpenOrNewAppendsWithinSameBucket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logrusrotate-timerotate-append")
+	if err != nil {
+		t.Fatalf("TempDir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	l1 := &Logger{logDir: dir, logfileBaseName: "app", RotationInterval: 24 * time.Hour}
+	if _, err := l1.Write([]byte("first\n")); err != nil {
+		t.Fatalf("Write: %s", err)
+	}
+	firstName := l1.logfileFullName
+	l1.Close()
+
+	// a second Logger instance in the same process (same pid) must resolve
+	// to the same bucket filename and append rather than truncate.
+	l2 := &Logger{logDir: dir, logfileBaseName: "app", RotationInterval: 24 * time.Hour}
+	if _, err := l2.Write([]byte("second\n")); err != nil {
+		t.Fatalf("Write: %s", err)
+	}
+	secondName := l2.logfileFullName
+	l2.Close()
+
+	if firstName != secondName {
+		t.Fatalf("bucket filenames differ across restarts: %q != %q", firstName, secondName)
+	}
+
+	content, err := ioutil.ReadFile(firstName)
+	if err != nil {
+		t.Fatalf("ReadFile: %s", err)
+	}
+	if string(content) != "first\nsecond\n" {
+		t.Fatalf("content = %q, want %q", content, "first\nsecond\n")
+	}
+}
+
+func TestSizeCapFallsBackToTimestampedName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logrusrotate-timerotate-sizecap")
+	if err != nil {
+		t.Fatalf("TempDir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := &Logger{
+		logDir:           dir,
+		logfileBaseName:  "app",
+		RotationInterval: 24 * time.Hour,
+		MaxSizeMb:        1,
+	}
+
+	bucketName := l.bucketFileName(l.currentBucket())
+	if _, err := l.Write([]byte("short\n")); err != nil {
+		t.Fatalf("Write: %s", err)
+	}
+	if l.logfileFullName != bucketName {
+		t.Fatalf("first write went to %q, want the bucket file %q", l.logfileFullName, bucketName)
+	}
+
+	// force a MaxSizeMb rotation without leaving the bucket: openNew must
+	// fall back to a uniquely timestamped name instead of truncating the
+	// bucket file out from under any reader still following it.
+	l.size = l.max()
+	if _, err := l.Write([]byte("overflow\n")); err != nil {
+		t.Fatalf("Write: %s", err)
+	}
+
+	if l.logfileFullName == bucketName {
+		t.Fatalf("size-capped rotation reused the bucket filename %q instead of a fallback name", bucketName)
+	}
+	if _, err := os.Stat(l.logfileFullName); err != nil {
+		t.Fatalf("expected fallback file %s to exist: %s", l.logfileFullName, err)
+	}
+
+	bucketContent, err := ioutil.ReadFile(bucketName)
+	if err != nil {
+		t.Fatalf("ReadFile bucket file: %s", err)
+	}
+	if string(bucketContent) != "short\n" {
+		t.Fatalf("bucket file content = %q, want %q (must be left untouched)", bucketContent, "short\n")
+	}
+}